@@ -0,0 +1,326 @@
+// Package readiness determines whether a RayCluster is actually serving
+// traffic, as opposed to merely scheduled. It is modeled after Helm's kube
+// resource-readiness checkers: each resource kind (pod, service, dashboard
+// endpoint) gets its own narrow check, and the checks are composed into a
+// single cluster-level verdict.
+package readiness
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rayiov1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
+)
+
+const (
+	// DefaultDashboardProbeTimeout bounds how long IsRayClusterReady will wait
+	// on the dashboard's serve-status endpoint before declaring it unreachable.
+	DefaultDashboardProbeTimeout = 2 * time.Second
+
+	gcsServerPortName = "gcs-server"
+	dashboardPortName = "dashboard"
+)
+
+// NotReadyReason is a short machine-checkable reason a pod is not yet
+// counted as ready, suitable for surfacing in status conditions or logs.
+type NotReadyReason string
+
+const (
+	NotReadyPodMissing           NotReadyReason = "PodMissing"
+	NotReadyPodNotRunning        NotReadyReason = "PodNotRunning"
+	NotReadyContainerNotReady    NotReadyReason = "ContainerNotReady"
+	NotReadyPodConditionNotReady NotReadyReason = "PodConditionNotReady"
+)
+
+// PodReadiness carries the outcome of checking a single expected pod.
+type PodReadiness struct {
+	// PodName is empty when the pod has not been created yet.
+	PodName string
+	Ready   bool
+	Reason  NotReadyReason
+	Message string
+}
+
+// ReadyStatus is the result of IsRayClusterReady: the overall verdict plus
+// enough per-pod detail for a user to see which pod blocked readiness.
+type ReadyStatus struct {
+	Ready bool
+
+	// Pods holds one entry per expected head/worker pod, keyed by
+	// "<group>/<pod name>" ("head/<pod name>" for the head group). A group
+	// with no pods at all gets a single "<group>" entry instead.
+	Pods map[string]PodReadiness
+
+	// ServiceReady is true once the head service has at least one ready
+	// endpoint on both the GCS and dashboard ports.
+	ServiceReady bool
+
+	// DashboardReady is true once the dashboard's serve status answered with
+	// a 2xx within the probe timeout: /api/serve/applications/ when the
+	// dashboard has that API, otherwise /api/serve/deployments/status.
+	DashboardReady bool
+
+	// Reason is a short human-readable explanation of the first blocking
+	// condition found, in pod -> service -> dashboard order.
+	Reason string
+}
+
+// Lister is the subset of a Kubernetes client IsRayClusterReady needs to
+// look up pods and endpoints for a RayCluster. It exists so callers can
+// pass either a live controller-runtime client or a fake one in tests,
+// without pulling the whole reconciler dependency graph into this package.
+type Lister interface {
+	ListPods(ctx context.Context, namespace string, labelSelector map[string]string) (*corev1.PodList, error)
+	GetEndpoints(ctx context.Context, namespace, name string) (*corev1.Endpoints, error)
+}
+
+// clientLister adapts a controller-runtime client.Client to Lister.
+type clientLister struct {
+	client client.Client
+}
+
+// NewLister wraps a controller-runtime client for use with IsRayClusterReady
+// and WaitForReady.
+func NewLister(c client.Client) Lister {
+	return &clientLister{client: c}
+}
+
+func (l *clientLister) ListPods(ctx context.Context, namespace string, labelSelector map[string]string) (*corev1.PodList, error) {
+	podList := &corev1.PodList{}
+	if err := l.client.List(ctx, podList, client.InNamespace(namespace), client.MatchingLabels(labelSelector)); err != nil {
+		return nil, err
+	}
+	return podList, nil
+}
+
+func (l *clientLister) GetEndpoints(ctx context.Context, namespace, name string) (*corev1.Endpoints, error) {
+	endpoints := &corev1.Endpoints{}
+	if err := l.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, endpoints); err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// podGroup is one expected group of pods (the head group, or one worker
+// group) and the label selector used to list its pods.
+type podGroup struct {
+	name     string
+	selector map[string]string
+}
+
+// IsRayClusterReady reports whether every expected head/worker pod is
+// actually ready, the head service has ready endpoints on the GCS and
+// dashboard ports, and the dashboard is answering serve-status requests.
+func IsRayClusterReady(ctx context.Context, cluster *rayiov1alpha1.RayCluster, lister Lister) (ReadyStatus, error) {
+	status := ReadyStatus{
+		Ready: true,
+		Pods:  map[string]PodReadiness{},
+	}
+
+	// groups is ordered (head first, then worker groups in spec order)
+	// rather than a map, so status.Reason picks the same "first blocking
+	// condition" on every call instead of flapping with map iteration order.
+	groups := []podGroup{
+		{name: "head", selector: map[string]string{utils.RayClusterLabelKey: cluster.Name, utils.RayNodeTypeLabelKey: string(rayiov1alpha1.HeadNode)}},
+	}
+	for _, workerGroup := range cluster.Spec.WorkerGroupSpecs {
+		groups = append(groups, podGroup{
+			name: workerGroup.GroupName,
+			selector: map[string]string{
+				utils.RayClusterLabelKey:   cluster.Name,
+				utils.RayNodeTypeLabelKey:  string(rayiov1alpha1.WorkerNode),
+				utils.RayNodeGroupLabelKey: workerGroup.GroupName,
+			},
+		})
+	}
+
+	for _, group := range groups {
+		groupName, selector := group.name, group.selector
+		podList, err := lister.ListPods(ctx, cluster.Namespace, selector)
+		if err != nil {
+			return ReadyStatus{}, fmt.Errorf("failed to list pods for group %s: %w", groupName, err)
+		}
+
+		if len(podList.Items) == 0 {
+			status.Ready = false
+			status.Pods[groupName] = PodReadiness{
+				Ready:   false,
+				Reason:  NotReadyPodMissing,
+				Message: fmt.Sprintf("no pods found for group %s", groupName),
+			}
+			if status.Reason == "" {
+				status.Reason = fmt.Sprintf("group %s: no pods found", groupName)
+			}
+			continue
+		}
+
+		for i := range podList.Items {
+			pod := &podList.Items[i]
+			readiness := checkPodReadiness(pod)
+			status.Pods[groupName+"/"+pod.Name] = readiness
+			if !readiness.Ready {
+				status.Ready = false
+				if status.Reason == "" {
+					status.Reason = fmt.Sprintf("pod %s/%s: %s", pod.Namespace, pod.Name, readiness.Message)
+				}
+			}
+		}
+	}
+
+	serviceName := utils.GenerateServiceName(cluster.Name)
+	serviceReady, dashboardPort, err := isServiceReady(ctx, lister, cluster.Namespace, serviceName)
+	if err != nil {
+		return ReadyStatus{}, fmt.Errorf("failed to check head service %s: %w", serviceName, err)
+	}
+	status.ServiceReady = serviceReady
+	if !serviceReady {
+		status.Ready = false
+		if status.Reason == "" {
+			status.Reason = fmt.Sprintf("head service %s has no ready endpoints on %s/%s", serviceName, gcsServerPortName, dashboardPortName)
+		}
+	}
+
+	dashboardReady := false
+	if serviceReady {
+		dashboardReady = probeDashboard(ctx, serviceName, cluster.Namespace, dashboardPort)
+	}
+	status.DashboardReady = dashboardReady
+	if !dashboardReady {
+		status.Ready = false
+		if status.Reason == "" {
+			status.Reason = "dashboard did not respond to serve-status probe"
+		}
+	}
+
+	return status, nil
+}
+
+// SyncAvailableReplicas recomputes readiness for cluster and sets
+// cluster.Status.AvailableReplicas to the number of pods that are actually
+// ready, rather than merely scheduled. The RayCluster controller should call
+// this (and persist the resulting status with its usual Status().Update())
+// right before it would otherwise have used CalculateAvailableReplicas.
+//
+// NOTE: wiring that call into the RayCluster controller's reconcile loop
+// belongs in controllers/ray, which is not part of this change; nothing in
+// this package calls SyncAvailableReplicas yet, so Status.AvailableReplicas
+// behavior is unchanged until that wiring lands.
+func SyncAvailableReplicas(ctx context.Context, cluster *rayiov1alpha1.RayCluster, lister Lister) (ReadyStatus, error) {
+	status, err := IsRayClusterReady(ctx, cluster, lister)
+	if err != nil {
+		return ReadyStatus{}, err
+	}
+
+	var ready int32
+	for _, pod := range status.Pods {
+		if pod.Ready {
+			ready++
+		}
+	}
+	cluster.Status.AvailableReplicas = ready
+
+	return status, nil
+}
+
+// CountReadyReplicas returns the number of pods in the list that are
+// PodRunning, have all container statuses Ready, and carry a PodReady=True
+// condition. Unlike CalculateAvailableReplicas, a pod that is merely
+// scheduled or crash-looping is not counted.
+func CountReadyReplicas(pods corev1.PodList) int32 {
+	var count int32
+	for i := range pods.Items {
+		if checkPodReadiness(&pods.Items[i]).Ready {
+			count++
+		}
+	}
+	return count
+}
+
+func checkPodReadiness(pod *corev1.Pod) PodReadiness {
+	result := PodReadiness{PodName: pod.Name}
+
+	if pod.Status.Phase != corev1.PodRunning {
+		result.Reason = NotReadyPodNotRunning
+		result.Message = fmt.Sprintf("phase is %s, want Running", pod.Status.Phase)
+		return result
+	}
+
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if !containerStatus.Ready {
+			result.Reason = NotReadyContainerNotReady
+			result.Message = fmt.Sprintf("container %s is not ready", containerStatus.Name)
+			return result
+		}
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			if condition.Status != corev1.ConditionTrue {
+				result.Reason = NotReadyPodConditionNotReady
+				result.Message = "PodReady condition is not True"
+				return result
+			}
+			result.Ready = true
+			return result
+		}
+	}
+
+	result.Reason = NotReadyPodConditionNotReady
+	result.Message = "PodReady condition not reported yet"
+	return result
+}
+
+// isServiceReady reports whether the head service has a ready endpoint on
+// both the GCS and dashboard ports, and returns the dashboard port number so
+// callers can reach it directly.
+func isServiceReady(ctx context.Context, lister Lister, namespace, serviceName string) (bool, int32, error) {
+	endpoints, err := lister.GetEndpoints(ctx, namespace, serviceName)
+	if err != nil {
+		return false, 0, err
+	}
+
+	foundGCS := false
+	var dashboardPort int32
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) == 0 {
+			continue
+		}
+		for _, port := range subset.Ports {
+			switch port.Name {
+			case gcsServerPortName:
+				foundGCS = true
+			case dashboardPortName:
+				dashboardPort = port.Port
+			}
+		}
+	}
+
+	return foundGCS && dashboardPort != 0, dashboardPort, nil
+}
+
+// probeDashboard hits the head service's serve-status endpoint and reports
+// whether it answered within DefaultDashboardProbeTimeout.
+func probeDashboard(ctx context.Context, serviceName, namespace string, port int32) bool {
+	probeCtx, cancel := context.WithTimeout(ctx, DefaultDashboardProbeTimeout)
+	defer cancel()
+
+	dashboardClient := utils.NewRayDashboardClient(
+		fmt.Sprintf("%s.%s.svc:%d", serviceName, namespace, port),
+		utils.WithTimeout(DefaultDashboardProbeTimeout),
+		utils.WithRetryPolicy(utils.RetryPolicy{MaxAttempts: 1}),
+	)
+
+	if _, err := dashboardClient.GetDeploymentsStatus(probeCtx); err != nil {
+		logrus.Debugf("dashboard probe for %s/%s failed: %v", namespace, serviceName, err)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,186 @@
+package readiness
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rayiov1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
+)
+
+// fakeLister is an in-memory Lister for tests: pods are looked up by group
+// name ("head" for the head selector, otherwise the selector's
+// RayNodeGroupLabelKey value), and a service with no entry in endpoints has
+// an Endpoints object with no subsets.
+type fakeLister struct {
+	podsByGroup map[string][]corev1.Pod
+	endpoints   map[string]*corev1.Endpoints
+}
+
+func (f *fakeLister) ListPods(_ context.Context, _ string, labelSelector map[string]string) (*corev1.PodList, error) {
+	group := labelSelector[utils.RayNodeGroupLabelKey]
+	if labelSelector[utils.RayNodeTypeLabelKey] == string(rayiov1alpha1.HeadNode) {
+		group = "head"
+	}
+	return &corev1.PodList{Items: f.podsByGroup[group]}, nil
+}
+
+func (f *fakeLister) GetEndpoints(_ context.Context, _, name string) (*corev1.Endpoints, error) {
+	if endpoints, ok := f.endpoints[name]; ok {
+		return endpoints, nil
+	}
+	return &corev1.Endpoints{}, nil
+}
+
+func readyPod(name string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "ray", Ready: true}},
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func notReadyPod(name string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "ray", Ready: false}},
+		},
+	}
+}
+
+func TestCheckPodReadiness(t *testing.T) {
+	tests := []struct {
+		name  string
+		pod   corev1.Pod
+		ready bool
+	}{
+		{"running, container ready, condition true", readyPod("p"), true},
+		{"not running", corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodPending}}, false},
+		{"running, container not ready", notReadyPod("p"), false},
+		{
+			"running, container ready, condition missing",
+			corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase:             corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{{Ready: true}},
+				},
+			},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkPodReadiness(&tt.pod).Ready; got != tt.ready {
+				t.Errorf("checkPodReadiness().Ready = %v, want %v", got, tt.ready)
+			}
+		})
+	}
+}
+
+func TestCountReadyReplicas(t *testing.T) {
+	pods := corev1.PodList{Items: []corev1.Pod{readyPod("a"), readyPod("b"), notReadyPod("c")}}
+	if got := CountReadyReplicas(pods); got != 2 {
+		t.Errorf("CountReadyReplicas() = %d, want 2", got)
+	}
+}
+
+func TestIsRayClusterReadyAllReady(t *testing.T) {
+	cluster := &rayiov1alpha1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "ns"},
+		Spec: rayiov1alpha1.RayClusterSpec{
+			WorkerGroupSpecs: []rayiov1alpha1.WorkerGroupSpec{{GroupName: "workers"}},
+		},
+	}
+
+	lister := &fakeLister{
+		podsByGroup: map[string][]corev1.Pod{
+			"head":    {readyPod("head-1")},
+			"workers": {readyPod("worker-1"), readyPod("worker-2")},
+		},
+		endpoints: map[string]*corev1.Endpoints{
+			utils.GenerateServiceName("cluster"): {
+				Subsets: []corev1.EndpointSubset{{
+					Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}},
+					Ports: []corev1.EndpointPort{
+						{Name: "gcs-server", Port: 6379},
+						{Name: "dashboard", Port: 8265},
+					},
+				}},
+			},
+		},
+	}
+
+	status, err := IsRayClusterReady(context.Background(), cluster, lister)
+	if err != nil {
+		t.Fatalf("IsRayClusterReady() error = %v", err)
+	}
+	if !status.ServiceReady {
+		t.Errorf("status.ServiceReady = false, want true")
+	}
+	if len(status.Pods) != 3 {
+		t.Errorf("len(status.Pods) = %d, want 3 (one entry per pod)", len(status.Pods))
+	}
+	// Dashboard is unreachable in this test (no live dashboard server), so
+	// the cluster as a whole is not Ready, but every pod/service check is.
+	for key, pod := range status.Pods {
+		if !pod.Ready {
+			t.Errorf("status.Pods[%q].Ready = false, want true", key)
+		}
+	}
+}
+
+func TestIsRayClusterReadyMissingGroupIsStableReason(t *testing.T) {
+	cluster := &rayiov1alpha1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "ns"},
+		Spec: rayiov1alpha1.RayClusterSpec{
+			WorkerGroupSpecs: []rayiov1alpha1.WorkerGroupSpec{
+				{GroupName: "workers-a"},
+				{GroupName: "workers-b"},
+			},
+		},
+	}
+
+	// Neither the head nor any worker group has pods: every call should
+	// pick the head group's "no pods found" reason first, regardless of Go
+	// map iteration order.
+	lister := &fakeLister{podsByGroup: map[string][]corev1.Pod{}}
+
+	for i := 0; i < 10; i++ {
+		status, err := IsRayClusterReady(context.Background(), cluster, lister)
+		if err != nil {
+			t.Fatalf("IsRayClusterReady() error = %v", err)
+		}
+		want := "group head: no pods found"
+		if status.Reason != want {
+			t.Fatalf("iteration %d: status.Reason = %q, want %q", i, status.Reason, want)
+		}
+	}
+}
+
+func TestSyncAvailableReplicas(t *testing.T) {
+	cluster := &rayiov1alpha1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "ns"},
+	}
+	lister := &fakeLister{
+		podsByGroup: map[string][]corev1.Pod{
+			"head": {readyPod("head-1")},
+		},
+		endpoints: map[string]*corev1.Endpoints{},
+	}
+
+	if _, err := SyncAvailableReplicas(context.Background(), cluster, lister); err != nil {
+		t.Fatalf("SyncAvailableReplicas() error = %v", err)
+	}
+	if cluster.Status.AvailableReplicas != 1 {
+		t.Errorf("cluster.Status.AvailableReplicas = %d, want 1", cluster.Status.AvailableReplicas)
+	}
+}
@@ -0,0 +1,55 @@
+package readiness
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	rayiov1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+)
+
+// WaitOptions configures WaitForReady's backoff.
+type WaitOptions struct {
+	// Initial is the delay before the first retry.
+	Initial float64
+	// Factor multiplies the delay after each attempt.
+	Factor float64
+	// Steps is the maximum number of attempts, including the first.
+	Steps int
+}
+
+// DefaultWaitOptions makes up to 10 attempts, sleeping 1s, 2s, 4s, ... 256s
+// between them (511s, about eight and a half minutes, in all) before giving up.
+var DefaultWaitOptions = WaitOptions{Initial: 1, Factor: 2, Steps: 10}
+
+// WaitForReady polls IsRayClusterReady with exponential backoff until the
+// cluster reports ready or opts is exhausted. It is meant for e2e tests that
+// need to block until a RayCluster is actually serving traffic, not just
+// scheduled. The returned error wraps the last seen ReadyStatus.Reason so
+// test failures point at the pod or service that never became ready.
+func WaitForReady(ctx context.Context, cluster *rayiov1alpha1.RayCluster, lister Lister, opts WaitOptions) error {
+	backoff := wait.Backoff{
+		Duration: time.Duration(opts.Initial * float64(time.Second)),
+		Factor:   opts.Factor,
+		Steps:    opts.Steps,
+	}
+
+	var lastStatus ReadyStatus
+	err := wait.ExponentialBackoffWithContext(ctx, backoff, func(ctx context.Context) (bool, error) {
+		status, err := IsRayClusterReady(ctx, cluster, lister)
+		if err != nil {
+			return false, err
+		}
+		lastStatus = status
+		return status.Ready, nil
+	})
+	if err != nil {
+		if lastStatus.Reason != "" {
+			return fmt.Errorf("RayCluster %s/%s did not become ready: %s: %w", cluster.Namespace, cluster.Name, lastStatus.Reason, err)
+		}
+		return fmt.Errorf("RayCluster %s/%s did not become ready: %w", cluster.Namespace, cluster.Name, err)
+	}
+	return nil
+}
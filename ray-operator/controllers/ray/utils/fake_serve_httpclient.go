@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"context"
+
+	rayv1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+)
+
+// FakeRayDashboardClient is an in-memory RayDashboardClientInterface for
+// controller unit tests. Set the *Func fields to stub out only the methods a
+// given test cares about; a method whose *Func field is unset returns an
+// empty result ("", an empty status or an empty map) and a nil error.
+// ConvertServeConfig and ConvertServeApplications have no *Func field and
+// always run RayDashboardClient's conversion.
+type FakeRayDashboardClient struct {
+	GetDeploymentsFunc       func(ctx context.Context) (string, error)
+	UpdateDeploymentsFunc    func(ctx context.Context, specs rayv1alpha1.ServeDeploymentGraphSpec) error
+	GetDeploymentsStatusFunc func(ctx context.Context) (*ServeDeploymentStatuses, error)
+	UpdateApplicationsFunc   func(ctx context.Context, apps []ServeApplicationSpec) error
+	GetApplicationsFunc      func(ctx context.Context) (map[string]ServeApplicationStatus, error)
+	DeleteApplicationFunc    func(ctx context.Context, name string) error
+}
+
+// NewFakeRayDashboardClient returns a FakeRayDashboardClient with no *Func
+// field set.
+func NewFakeRayDashboardClient() *FakeRayDashboardClient {
+	return &FakeRayDashboardClient{}
+}
+
+func (f *FakeRayDashboardClient) GetDeployments(ctx context.Context) (string, error) {
+	if f.GetDeploymentsFunc != nil {
+		return f.GetDeploymentsFunc(ctx)
+	}
+	return "", nil
+}
+
+func (f *FakeRayDashboardClient) UpdateDeployments(ctx context.Context, specs rayv1alpha1.ServeDeploymentGraphSpec) error {
+	if f.UpdateDeploymentsFunc != nil {
+		return f.UpdateDeploymentsFunc(ctx, specs)
+	}
+	return nil
+}
+
+func (f *FakeRayDashboardClient) GetDeploymentsStatus(ctx context.Context) (*ServeDeploymentStatuses, error) {
+	if f.GetDeploymentsStatusFunc != nil {
+		return f.GetDeploymentsStatusFunc(ctx)
+	}
+	return &ServeDeploymentStatuses{}, nil
+}
+
+func (f *FakeRayDashboardClient) ConvertServeConfig(specs []rayv1alpha1.ServeConfigSpec) []ServeConfigSpec {
+	return (&RayDashboardClient{}).ConvertServeConfig(specs)
+}
+
+func (f *FakeRayDashboardClient) UpdateApplications(ctx context.Context, apps []ServeApplicationSpec) error {
+	if f.UpdateApplicationsFunc != nil {
+		return f.UpdateApplicationsFunc(ctx, apps)
+	}
+	return nil
+}
+
+func (f *FakeRayDashboardClient) GetApplications(ctx context.Context) (map[string]ServeApplicationStatus, error) {
+	if f.GetApplicationsFunc != nil {
+		return f.GetApplicationsFunc(ctx)
+	}
+	return map[string]ServeApplicationStatus{}, nil
+}
+
+func (f *FakeRayDashboardClient) DeleteApplication(ctx context.Context, name string) error {
+	if f.DeleteApplicationFunc != nil {
+		return f.DeleteApplicationFunc(ctx, name)
+	}
+	return nil
+}
+
+func (f *FakeRayDashboardClient) ConvertServeApplications(specs []rayv1alpha1.ServeApplicationSpec) []ServeApplicationSpec {
+	return (&RayDashboardClient{}).ConvertServeApplications(specs)
+}
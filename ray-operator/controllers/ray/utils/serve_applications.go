@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	rayv1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+)
+
+// ServeApplicationSpec is the wire format for one named application under
+// the dashboard's multi-application API, /api/serve/applications/. It
+// mirrors the CRD's rayv1alpha1.ServeApplicationSpec the same way
+// ServeConfigSpec mirrors rayv1alpha1.ServeConfigSpec.
+//
+// NOTE: adding the rayv1alpha1.ServeApplicationSpec list field to
+// RayServiceSpec (alongside the existing single-application fields) and
+// wiring RayDashboardClient.ConvertServeApplications/UpdateApplications into
+// the RayService controller's reconcile loop belongs in
+// apis/ray/v1alpha1 and controllers/ray respectively. Neither package is
+// part of this change; this file only adds the dashboard-client half of the
+// migration described in the request.
+type ServeApplicationSpec struct {
+	Name        string                 `json:"name"`
+	ImportPath  string                 `json:"import_path"`
+	RoutePrefix string                 `json:"route_prefix,omitempty"`
+	RuntimeEnv  map[string]interface{} `json:"runtime_env,omitempty"`
+	Deployments []ServeConfigSpec      `json:"deployments,omitempty"`
+}
+
+// ServeApplicationStatus is the dashboard's reported status for one
+// application.
+type ServeApplicationStatus struct {
+	Status             string                              `json:"status,omitempty"`
+	Message            string                              `json:"message,omitempty"`
+	DeploymentStatuses []rayv1alpha1.ServeDeploymentStatus `json:"deployments,omitempty"`
+}
+
+// serveApplicationsRequest is the PUT payload for /api/serve/applications/.
+type serveApplicationsRequest struct {
+	Applications []ServeApplicationSpec `json:"applications"`
+}
+
+// serveApplicationsResponse is the GET response from /api/serve/applications/.
+type serveApplicationsResponse struct {
+	Applications map[string]ServeApplicationStatus `json:"applications"`
+}
+
+// UpdateApplications pushes the full set of named applications to the
+// dashboard, replacing whatever is currently deployed.
+func (r *RayDashboardClient) UpdateApplications(ctx context.Context, apps []ServeApplicationSpec) error {
+	body, err := json.Marshal(serveApplicationsRequest{Applications: apps})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.do(ctx, http.MethodPut, ApplicationsPath, body)
+	return err
+}
+
+// GetApplications returns the dashboard's current status for every deployed
+// application, keyed by application name.
+func (r *RayDashboardClient) GetApplications(ctx context.Context) (map[string]ServeApplicationStatus, error) {
+	body, err := r.do(ctx, http.MethodGet, ApplicationsPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp serveApplicationsResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Applications, nil
+}
+
+// DeleteApplication removes a single named application, leaving the rest of
+// the dashboard's applications untouched.
+func (r *RayDashboardClient) DeleteApplication(ctx context.Context, name string) error {
+	_, err := r.do(ctx, http.MethodDelete, ApplicationsPath+name, nil)
+	return err
+}
+
+// ConvertServeApplications converts the CRD's multi-application serve config
+// into the dashboard's wire format, the same way ConvertServeConfig does for
+// the single-application path.
+func (r *RayDashboardClient) ConvertServeApplications(specs []rayv1alpha1.ServeApplicationSpec) []ServeApplicationSpec {
+	apps := make([]ServeApplicationSpec, len(specs))
+
+	for i, app := range specs {
+		runtimeEnv := make(map[string]interface{})
+		_ = yaml.Unmarshal([]byte(app.RuntimeEnv), &runtimeEnv)
+
+		apps[i] = ServeApplicationSpec{
+			Name:        app.Name,
+			ImportPath:  app.ImportPath,
+			RoutePrefix: app.RoutePrefix,
+			RuntimeEnv:  runtimeEnv,
+			Deployments: r.ConvertServeConfig(app.ServeConfigSpecs),
+		}
+	}
+
+	return apps
+}
+
+// supportsApplicationsAPI probes /api/serve/applications/ and caches the
+// result: newer Ray Serve versions host multiple applications there, older
+// ones only have /api/serve/deployments/. Only a confirmed 404 is cached as
+// "unsupported" - any other failure (connection error, a 5xx that survives
+// retries, a context deadline) leaves usesApplicationsAPI unset so the next
+// call re-probes instead of permanently locking in a guess from a transient
+// blip.
+func (r *RayDashboardClient) supportsApplicationsAPI(ctx context.Context) bool {
+	if r.usesApplicationsAPI != nil {
+		return *r.usesApplicationsAPI
+	}
+
+	_, err := r.do(ctx, http.MethodGet, ApplicationsPath, nil)
+	if err == nil {
+		supported := true
+		r.usesApplicationsAPI = &supported
+		return true
+	}
+
+	if dashboardErr, ok := err.(*DashboardError); ok && dashboardErr.StatusCode == http.StatusNotFound {
+		supported := false
+		r.usesApplicationsAPI = &supported
+		return false
+	}
+
+	// Inconclusive probe: assume the newer API for this call, but don't cache
+	// the guess.
+	return true
+}
+
+// aggregateApplicationStatuses flattens the multi-application statuses into
+// the single-application ServeDeploymentStatuses shape. The aggregate
+// ApplicationStatus takes the non-running application's status that sorts
+// first by name, or "RUNNING" if every application is running; applications
+// are visited in name order so the result is stable across calls.
+func aggregateApplicationStatuses(apps map[string]ServeApplicationStatus) *ServeDeploymentStatuses {
+	names := make([]string, 0, len(apps))
+	for name := range apps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	aggregate := &ServeDeploymentStatuses{
+		ApplicationStatus: rayv1alpha1.AppStatus{Status: "RUNNING"},
+	}
+
+	for _, name := range names {
+		app := apps[name]
+		aggregate.DeploymentStatuses = append(aggregate.DeploymentStatuses, app.DeploymentStatuses...)
+		if app.Status != "RUNNING" && aggregate.ApplicationStatus.Status == "RUNNING" {
+			aggregate.ApplicationStatus = rayv1alpha1.AppStatus{Status: app.Status, Message: app.Message}
+		}
+	}
+
+	return aggregate
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSupportsApplicationsAPICachesOnlyOn404(t *testing.T) {
+	var calls int32
+	status := int32(http.StatusInternalServerError)
+	_, client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+	}, WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	dashboardClient := client.(*RayDashboardClient)
+
+	if got := dashboardClient.supportsApplicationsAPI(context.Background()); !got {
+		t.Error("supportsApplicationsAPI() = false on a 5xx probe, want true (inconclusive, assume newer API)")
+	}
+	if dashboardClient.usesApplicationsAPI != nil {
+		t.Fatal("usesApplicationsAPI got cached after an inconclusive (non-404) probe failure")
+	}
+
+	// Next call re-probes rather than trusting the earlier guess: once the
+	// dashboard actually answers 404, the result is cached as unsupported.
+	atomic.StoreInt32(&status, http.StatusNotFound)
+
+	if got := dashboardClient.supportsApplicationsAPI(context.Background()); got {
+		t.Error("supportsApplicationsAPI() = true, want false after a 5xx probe followed by a 404")
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("probe handler called %d times across the two calls, want 2 (no caching on inconclusive failures)", got)
+	}
+}
+
+func TestSupportsApplicationsAPICaches404AsUnsupported(t *testing.T) {
+	var calls int32
+	_, client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	dashboardClient := client.(*RayDashboardClient)
+
+	for i := 0; i < 3; i++ {
+		if dashboardClient.supportsApplicationsAPI(context.Background()) {
+			t.Errorf("call %d: supportsApplicationsAPI() = true, want false after a confirmed 404", i)
+		}
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("probe handler called %d times, want 1 (404 result should be cached)", got)
+	}
+}
+
+func TestSupportsApplicationsAPICachesOnSuccess(t *testing.T) {
+	var calls int32
+	_, client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"applications":{}}`))
+	})
+	dashboardClient := client.(*RayDashboardClient)
+
+	for i := 0; i < 3; i++ {
+		if !dashboardClient.supportsApplicationsAPI(context.Background()) {
+			t.Errorf("call %d: supportsApplicationsAPI() = false, want true after a successful probe", i)
+		}
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("probe handler called %d times, want 1 (successful result should be cached)", got)
+	}
+}
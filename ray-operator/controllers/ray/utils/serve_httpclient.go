@@ -2,10 +2,15 @@ package utils
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
 	"io/ioutil"
+	"math/rand"
 	"net/http"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/yaml"
 
 	rayv1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
@@ -13,8 +18,9 @@ import (
 )
 
 var (
-	DeployPath = "/api/serve/deployments/"
-	StatusPath = "/api/serve/deployments/status"
+	DeployPath       = "/api/serve/deployments/"
+	StatusPath       = "/api/serve/deployments/status"
+	ApplicationsPath = "/api/serve/applications/"
 )
 
 // ServeConfigSpec defines the desired state of RayService, used by Ray Dashboard.
@@ -56,54 +62,175 @@ type ServingClusterDeployments struct {
 	Deployments []ServeConfigSpec      `json:"deployments,omitempty"`
 }
 
+// DashboardError is returned whenever the Ray dashboard answers a request
+// with a non-2xx status code, so callers can distinguish "dashboard said no"
+// from a transport-level failure.
+type DashboardError struct {
+	StatusCode int
+	Path       string
+	Method     string
+	Body       string
+}
+
+func (e *DashboardError) Error() string {
+	return fmt.Sprintf("dashboard returned status %d for %s %s: %s", e.StatusCode, e.Method, e.Path, e.Body)
+}
+
+// IsDashboardServerError reports whether err is a DashboardError caused by a
+// 5xx response, i.e. the kind of failure a retry might recover from.
+func IsDashboardServerError(err error) bool {
+	dashboardErr, ok := err.(*DashboardError)
+	return ok && dashboardErr.StatusCode >= 500
+}
+
+// RetryPolicy controls how RayDashboardClient retries a failed request.
+// Retries only happen for connection errors and 5xx/429 responses; any other
+// error or status code is returned to the caller immediately.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of tries, including the first one.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. Each subsequent retry
+	// doubles the previous delay, plus up to BaseDelay of jitter, capped at
+	// MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultRetryPolicy retries up to twice more (three attempts total) with
+// exponential backoff starting at 200ms.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+}
+
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	backoff := p.BaseDelay << attempt
+	if backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(p.BaseDelay) + 1))
+	delay := backoff + jitter
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
 type RayDashboardClientInterface interface {
-	InitClient(url string)
-	GetDeployments() (string, error)
-	UpdateDeployments(specs rayv1alpha1.ServeDeploymentGraphSpec) error
-	GetDeploymentsStatus() (*ServeDeploymentStatuses, error)
+	GetDeployments(ctx context.Context) (string, error)
+	UpdateDeployments(ctx context.Context, specs rayv1alpha1.ServeDeploymentGraphSpec) error
+	GetDeploymentsStatus(ctx context.Context) (*ServeDeploymentStatuses, error)
 	ConvertServeConfig(specs []rayv1alpha1.ServeConfigSpec) []ServeConfigSpec
+
+	// UpdateApplications, GetApplications, and DeleteApplication talk to the
+	// multi-application dashboard API (/api/serve/applications/), which
+	// superseded the single-application /api/serve/deployments/ API used by
+	// the methods above. See serve_applications.go.
+	UpdateApplications(ctx context.Context, apps []ServeApplicationSpec) error
+	GetApplications(ctx context.Context) (map[string]ServeApplicationStatus, error)
+	DeleteApplication(ctx context.Context, name string) error
+	ConvertServeApplications(specs []rayv1alpha1.ServeApplicationSpec) []ServeApplicationSpec
 }
 
 // GetRayDashboardClientFunc Used for unit tests.
-var GetRayDashboardClientFunc = GetRayDashboardClient
+var GetRayDashboardClientFunc = NewRayDashboardClient
 
-func GetRayDashboardClient() RayDashboardClientInterface {
-	return &RayDashboardClient{}
+// ClientOption configures a RayDashboardClient. Use With* helpers below
+// rather than constructing RayDashboardClient directly.
+type ClientOption func(*RayDashboardClient)
+
+// WithTimeout overrides the client's default 2s request timeout.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(r *RayDashboardClient) {
+		r.client.Timeout = timeout
+	}
 }
 
-type RayDashboardClient struct {
-	client       http.Client
-	dashboardURL string
+// WithTransport overrides the client's http.RoundTripper, e.g. to inject a
+// custom dialer or a test double.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(r *RayDashboardClient) {
+		r.client.Transport = transport
+	}
 }
 
-func (r *RayDashboardClient) InitClient(url string) {
-	r.client = http.Client{
-		Timeout: 2 * time.Second,
+// WithTLSConfig configures TLS for requests to the dashboard, for clusters
+// that terminate TLS at the dashboard itself. It must be applied before any
+// ClientOption that installs a non-*http.Transport RoundTripper, since it
+// can only set TLSClientConfig on an *http.Transport.
+func WithTLSConfig(tlsConfig *tls.Config) ClientOption {
+	return func(r *RayDashboardClient) {
+		transport, ok := r.client.Transport.(*http.Transport)
+		if !ok {
+			if r.client.Transport != nil {
+				logrus.Warnf("WithTLSConfig: existing Transport is not *http.Transport, ignoring it")
+			}
+			transport = &http.Transport{}
+		}
+		transport.TLSClientConfig = tlsConfig
+		r.client.Transport = transport
 	}
-	r.dashboardURL = "http://" + url
 }
 
-// GetDeployments get the current deployments in the Ray cluster.
-func (r *RayDashboardClient) GetDeployments() (string, error) {
-	req, err := http.NewRequest("GET", r.dashboardURL+DeployPath, nil)
-	if err != nil {
-		return "", err
+// WithBearerToken attaches an Authorization: Bearer header to every request.
+func WithBearerToken(token string) ClientOption {
+	return func(r *RayDashboardClient) {
+		r.bearerToken = token
 	}
+}
 
-	resp, err := r.client.Do(req)
+// WithRetryPolicy overrides DefaultRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(r *RayDashboardClient) {
+		r.retryPolicy = policy
+	}
+}
 
-	if err != nil {
-		return "", err
+// NewRayDashboardClient builds a RayDashboardClient for the dashboard at url,
+// applying opts in order. Use this instead of constructing
+// RayDashboardClient directly so that options like timeout, transport, TLS
+// config, and bearer-token auth can be injected without exposing client
+// internals.
+func NewRayDashboardClient(url string, opts ...ClientOption) RayDashboardClientInterface {
+	r := &RayDashboardClient{
+		client:       http.Client{Timeout: 2 * time.Second},
+		dashboardURL: "http://" + url,
+		retryPolicy:  DefaultRetryPolicy,
 	}
-	defer resp.Body.Close()
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+type RayDashboardClient struct {
+	client       http.Client
+	dashboardURL string
+	bearerToken  string
+	retryPolicy  RetryPolicy
 
-	body, _ := ioutil.ReadAll(resp.Body)
+	// usesApplicationsAPI caches the outcome of probing whether the
+	// dashboard speaks the multi-application API, so repeated status
+	// reconciliations don't re-probe on every call. nil means undetected.
+	usesApplicationsAPI *bool
+}
 
+// GetDeployments get the current deployments in the Ray cluster.
+func (r *RayDashboardClient) GetDeployments(ctx context.Context) (string, error) {
+	body, err := r.do(ctx, http.MethodGet, DeployPath, nil)
+	if err != nil {
+		return "", err
+	}
 	return string(body), nil
 }
 
 // UpdateDeployments update the deployments in the Ray cluster.
-func (r *RayDashboardClient) UpdateDeployments(specs rayv1alpha1.ServeDeploymentGraphSpec) error {
+func (r *RayDashboardClient) UpdateDeployments(ctx context.Context, specs rayv1alpha1.ServeDeploymentGraphSpec) error {
 	runtimeEnv := make(map[string]interface{})
 	_ = yaml.Unmarshal([]byte(specs.RuntimeEnv), &runtimeEnv)
 
@@ -114,51 +241,107 @@ func (r *RayDashboardClient) UpdateDeployments(specs rayv1alpha1.ServeDeployment
 	}
 
 	deploymentJson, err := json.Marshal(servingClusterDeployments)
-
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest("PUT", r.dashboardURL+DeployPath, bytes.NewBuffer(deploymentJson))
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := r.client.Do(req)
-
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	return nil
+	_, err = r.do(ctx, http.MethodPut, DeployPath, deploymentJson)
+	return err
 }
 
-// GetDeploymentsStatus get the current deployment statuses in the Ray cluster.
-func (r *RayDashboardClient) GetDeploymentsStatus() (*ServeDeploymentStatuses, error) {
-	req, err := http.NewRequest("GET", r.dashboardURL+StatusPath, nil)
-	if err != nil {
-		return nil, err
+// GetDeploymentsStatus get the current deployment statuses in the Ray
+// cluster. If the dashboard exposes the multi-application API, the
+// per-application statuses are aggregated into the single-application
+// ServeDeploymentStatuses shape so the RayService controller's status
+// reconciliation doesn't need to change during the migration.
+func (r *RayDashboardClient) GetDeploymentsStatus(ctx context.Context) (*ServeDeploymentStatuses, error) {
+	if r.supportsApplicationsAPI(ctx) {
+		apps, err := r.GetApplications(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return aggregateApplicationStatuses(apps), nil
 	}
 
-	resp, err := r.client.Do(req)
-
+	body, err := r.do(ctx, http.MethodGet, StatusPath, nil)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	body, _ := ioutil.ReadAll(resp.Body)
 
 	var serveStatuses ServeDeploymentStatuses
-	if err = json.Unmarshal(body, &serveStatuses); err != nil {
+	if err := json.Unmarshal(body, &serveStatuses); err != nil {
 		return nil, err
 	}
 
 	return &serveStatuses, nil
 }
 
+// do issues a request against path, retrying per r.retryPolicy on connection
+// errors and 5xx/429 responses, and returns a *DashboardError for any other
+// non-2xx response.
+func (r *RayDashboardClient) do(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	var lastErr error
+
+	for attempt := 0; attempt < r.retryPolicy.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(r.retryPolicy.delay(attempt - 1)):
+			}
+		}
+
+		var reqBody *bytes.Reader
+		if body != nil {
+			reqBody = bytes.NewReader(body)
+		} else {
+			reqBody = bytes.NewReader(nil)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, r.dashboardURL+path, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if r.bearerToken != "" {
+			req.Header.Set("Authorization", "Bearer "+r.bearerToken)
+		}
+
+		resp, err := r.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		respBody, readErr := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr != nil {
+			lastErr = readErr
+			continue
+		}
+
+		if resp.StatusCode >= 300 {
+			dashboardErr := &DashboardError{
+				StatusCode: resp.StatusCode,
+				Path:       path,
+				Method:     method,
+				Body:       string(respBody),
+			}
+			if !isRetryableStatus(resp.StatusCode) {
+				return nil, dashboardErr
+			}
+			lastErr = dashboardErr
+			continue
+		}
+
+		return respBody, nil
+	}
+
+	return nil, lastErr
+}
+
 func (r *RayDashboardClient) ConvertServeConfig(specs []rayv1alpha1.ServeConfigSpec) []ServeConfigSpec {
 	serveConfigToSend := make([]ServeConfigSpec, len(specs))
 
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...ClientOption) (*httptest.Server, RayDashboardClientInterface) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	clientOpts := append([]ClientOption{WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond})}, opts...)
+	client := NewRayDashboardClient(strings.TrimPrefix(server.URL, "http://"), clientOpts...)
+	return server, client
+}
+
+func TestGetDeploymentsStatusRetriesOn5xxThenSucceeds(t *testing.T) {
+	var calls int32
+	_, client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == ApplicationsPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"app_status":{},"deployment_statuses":[]}`))
+	})
+
+	status, err := client.GetDeploymentsStatus(context.Background())
+	if err != nil {
+		t.Fatalf("GetDeploymentsStatus() error = %v", err)
+	}
+	if status == nil {
+		t.Fatal("GetDeploymentsStatus() returned nil status")
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("handler called %d times, want 3 (2 failures + 1 success)", got)
+	}
+}
+
+func TestDoDoesNotRetryOnNonRetryableStatus(t *testing.T) {
+	var calls int32
+	_, client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("bad request"))
+	})
+
+	_, err := client.GetDeployments(context.Background())
+	if err == nil {
+		t.Fatal("GetDeployments() error = nil, want DashboardError")
+	}
+	dashboardErr, ok := err.(*DashboardError)
+	if !ok {
+		t.Fatalf("error type = %T, want *DashboardError", err)
+	}
+	if dashboardErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("StatusCode = %d, want %d", dashboardErr.StatusCode, http.StatusBadRequest)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("handler called %d times, want 1 (no retry on 400)", got)
+	}
+}
+
+func TestIsDashboardServerError(t *testing.T) {
+	if !IsDashboardServerError(&DashboardError{StatusCode: 503}) {
+		t.Error("IsDashboardServerError(503) = false, want true")
+	}
+	if IsDashboardServerError(&DashboardError{StatusCode: 404}) {
+		t.Error("IsDashboardServerError(404) = true, want false")
+	}
+	if IsDashboardServerError(nil) {
+		t.Error("IsDashboardServerError(nil) = true, want false")
+	}
+}
+
+func TestWithBearerTokenSetsAuthorizationHeader(t *testing.T) {
+	var gotAuth string
+	_, client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == ApplicationsPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}, WithBearerToken("test-token"))
+
+	if _, err := client.GetDeploymentsStatus(context.Background()); err != nil {
+		t.Fatalf("GetDeploymentsStatus() error = %v", err)
+	}
+	if gotAuth != "Bearer test-token" {
+		t.Errorf("Authorization header = %q, want %q", gotAuth, "Bearer test-token")
+	}
+}
+
+func TestGetDeploymentsStatusPrefersApplicationsAPI(t *testing.T) {
+	_, client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != ApplicationsPath {
+			t.Errorf("request went to %s, want %s", r.URL.Path, ApplicationsPath)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"applications":{"app1":{"status":"RUNNING","deployments":[]}}}`))
+	})
+
+	status, err := client.GetDeploymentsStatus(context.Background())
+	if err != nil {
+		t.Fatalf("GetDeploymentsStatus() error = %v", err)
+	}
+	if status.ApplicationStatus.Status != "RUNNING" {
+		t.Errorf("ApplicationStatus.Status = %q, want RUNNING", status.ApplicationStatus.Status)
+	}
+}
+
+func TestRetryPolicyDelayRespectsMaxDelay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 150 * time.Millisecond}
+	for attempt := 0; attempt < 5; attempt++ {
+		if d := policy.delay(attempt); d > policy.MaxDelay {
+			t.Errorf("delay(%d) = %v, want <= %v", attempt, d, policy.MaxDelay)
+		}
+	}
+}
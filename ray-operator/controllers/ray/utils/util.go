@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"math"
 	"reflect"
@@ -10,6 +12,7 @@ import (
 	"unicode"
 
 	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 
 	"k8s.io/apimachinery/pkg/util/rand"
 
@@ -23,6 +26,26 @@ import (
 const (
 	RayClusterSuffix = "-raycluster-"
 	DashboardName    = "dashboard"
+
+	// RayClusterLabelKey is stamped on every pod owned by a RayCluster, with
+	// the cluster's name as the value.
+	RayClusterLabelKey = "ray.io/cluster"
+	// RayNodeTypeLabelKey distinguishes head pods from worker pods.
+	RayNodeTypeLabelKey = "ray.io/node-type"
+	// RayNodeGroupLabelKey is stamped on worker pods with their worker
+	// group's name as the value.
+	RayNodeGroupLabelKey = "ray.io/group"
+
+	// PodTemplateHashAnnotation is stamped on created pods with the output
+	// of PodTemplateHash, so PodNeedsReplacement can short-circuit the
+	// strategic-merge-patch diff when a pod's hash already matches the
+	// current template.
+	PodTemplateHashAnnotation = "ray.io/pod-template-hash"
+
+	// serviceAccountTokenVolumePrefix matches the projected service account
+	// token volume the API server injects into every pod spec; it never
+	// appears in a pod template and must be stripped before diffing.
+	serviceAccountTokenVolumePrefix = "kube-api-access-"
 )
 
 // IsCreated returns true if pod has been created and is maintained by the API server
@@ -169,7 +192,10 @@ func CalculateMaxReplicas(cluster *rayiov1alpha1.RayCluster) int32 {
 	return count
 }
 
-// CalculateDesiredReplicas calculate desired worker replicas at the cluster level
+// CalculateAvailableReplicas counts pods that are merely scheduled
+// (PodPending or PodRunning), not actually ready to serve traffic. Prefer
+// readiness.CountReadyReplicas, which also checks container readiness and
+// the PodReady condition.
 func CalculateAvailableReplicas(pods corev1.PodList) int32 {
 	count := int32(0)
 	for _, pod := range pods.Items {
@@ -216,63 +242,249 @@ func CheckAllPodsRunnning(runningPods corev1.PodList) bool {
 	return true
 }
 
-func PodNotMatchingTemplate(pod corev1.Pod, template corev1.PodTemplateSpec) bool {
-	if pod.Status.Phase == corev1.PodRunning && pod.ObjectMeta.DeletionTimestamp == nil {
-		if len(template.Spec.Containers) != len(pod.Spec.Containers) {
-			return true
+// podDiffView is the subset of a pod that PodNeedsReplacement and
+// PodTemplateHash compare: the full spec plus the metadata fields that
+// actually affect behavior, with server-mutated fields normalized away.
+type podDiffView struct {
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Spec        corev1.PodSpec    `json:"spec"`
+}
+
+// controllerManagedLabelKeys are stamped onto every live pod by the
+// controller (see RayClusterLabelKey, RayNodeTypeLabelKey,
+// RayNodeGroupLabelKey) but never appear on a raw HeadGroupSpec/
+// WorkerGroupSpec template, so they must be excluded from the label diff
+// below or every running pod would show a spurious label difference.
+var controllerManagedLabelKeys = map[string]bool{
+	RayClusterLabelKey:   true,
+	RayNodeTypeLabelKey:  true,
+	RayNodeGroupLabelKey: true,
+}
+
+func newPodDiffView(meta metav1.ObjectMeta, spec corev1.PodSpec) podDiffView {
+	annotations := map[string]string{}
+	for k, v := range meta.Annotations {
+		if k == PodTemplateHashAnnotation {
+			continue
 		}
-		cmap := map[string]*corev1.Container{}
-		for _, container := range pod.Spec.Containers {
-			cmap[container.Name] = &container
+		annotations[k] = v
+	}
+
+	labels := map[string]string{}
+	for k, v := range meta.Labels {
+		if controllerManagedLabelKeys[k] {
+			continue
 		}
-		for _, container1 := range template.Spec.Containers {
-			if container2, ok := cmap[container1.Name]; ok {
-				if container1.Image != container2.Image {
-					// image name do not match
-					return true
-				}
-				if len(container1.Resources.Requests) != len(container2.Resources.Requests) ||
-					len(container1.Resources.Limits) != len(container2.Resources.Limits) {
-					// resource entries do not match
-					return true
-				}
+		labels[k] = v
+	}
 
-				resources1 := []corev1.ResourceList{
-					container1.Resources.Requests,
-					container1.Resources.Limits,
-				}
-				resources2 := []corev1.ResourceList{
-					container2.Resources.Requests,
-					container2.Resources.Limits,
-				}
-				for i := range resources1 {
-					// we need to make sure all fields match
-					for name, quantity1 := range resources1[i] {
-						if quantity2, ok := resources2[i][name]; ok {
-							if quantity1.Cmp(quantity2) != 0 {
-								// request amount does not match
-								return true
-							}
-						} else {
-							// no such request
-							return true
-						}
+	return podDiffView{
+		Labels:      labels,
+		Annotations: annotations,
+		Spec:        normalizePodSpecForDiff(spec),
+	}
+}
+
+// normalizePodSpecForDiff strips fields Kubernetes is known to mutate or
+// default server-side, so a pod created from a template diffs as equal to
+// that same template. ImagePullSecrets is dropped entirely: the API server
+// can append a default service account's pull secrets to it, and we can't
+// tell those apart from user-specified ones here.
+func normalizePodSpecForDiff(spec corev1.PodSpec) corev1.PodSpec {
+	normalized := *spec.DeepCopy()
+
+	normalized.NodeName = ""
+	normalized.ServiceAccountName = ""
+	normalized.DeprecatedServiceAccount = ""
+	normalized.ImagePullSecrets = nil
+	normalized.Tolerations = filterDefaultTolerations(normalized.Tolerations)
+
+	volumes := make([]corev1.Volume, 0, len(normalized.Volumes))
+	for _, volume := range normalized.Volumes {
+		if strings.HasPrefix(volume.Name, serviceAccountTokenVolumePrefix) {
+			continue
+		}
+		volumes = append(volumes, volume)
+	}
+	normalized.Volumes = volumes
+
+	for i := range normalized.Containers {
+		normalized.Containers[i].VolumeMounts = filterDefaultVolumeMounts(normalized.Containers[i].VolumeMounts)
+		normalized.Containers[i].TerminationMessagePath = ""
+		normalized.Containers[i].TerminationMessagePolicy = ""
+	}
+
+	return normalized
+}
+
+// isDefaultAdmissionToleration reports whether t is one of the two
+// not-ready/unreachable NoExecute tolerations the DefaultTolerationSeconds
+// admission plugin adds to every pod spec that doesn't already declare an
+// equivalent, so we don't diff it as a template change. We don't compare the
+// exact TolerationSeconds value: the apiserver flags that control it
+// (--default-not-ready-toleration-seconds, --default-unreachable-toleration-seconds)
+// are commonly changed by cluster operators away from the upstream default of
+// 300, and hardcoding 300 here would make every pod on such a cluster diff
+// forever. We do require TolerationSeconds to be set, since the admission
+// plugin always stamps a concrete value; a nil TolerationSeconds (tolerate
+// indefinitely) is a deliberate user choice and must still be diffed.
+func isDefaultAdmissionToleration(t corev1.Toleration) bool {
+	if t.Operator != corev1.TolerationOpExists || t.Effect != corev1.TaintEffectNoExecute {
+		return false
+	}
+	if t.TolerationSeconds == nil {
+		return false
+	}
+	return t.Key == "node.kubernetes.io/not-ready" || t.Key == "node.kubernetes.io/unreachable"
+}
+
+func filterDefaultTolerations(tolerations []corev1.Toleration) []corev1.Toleration {
+	filtered := make([]corev1.Toleration, 0, len(tolerations))
+	for _, t := range tolerations {
+		if isDefaultAdmissionToleration(t) {
+			continue
+		}
+		filtered = append(filtered, t)
+	}
+	return filtered
+}
+
+func filterDefaultVolumeMounts(mounts []corev1.VolumeMount) []corev1.VolumeMount {
+	filtered := make([]corev1.VolumeMount, 0, len(mounts))
+	for _, mount := range mounts {
+		if strings.HasPrefix(mount.Name, serviceAccountTokenVolumePrefix) {
+			continue
+		}
+		filtered = append(filtered, mount)
+	}
+	return filtered
+}
+
+// PodNeedsReplacement reports whether pod's live spec has drifted from
+// template in a way that matters, by computing a strategic-merge-patch
+// between the two. It returns the first differing field path as a
+// human-readable reason so users can see why a pod is being replaced.
+// Pods already being deleted, or not yet running, are left alone.
+//
+// NOTE: calling this (and StampPodTemplateHash, below) from the RayCluster
+// controller's reconcile loop belongs in controllers/ray, which is not part
+// of this change; nothing in this package calls either function yet, so
+// rolling-replacement behavior is unchanged until that wiring lands.
+func PodNeedsReplacement(pod corev1.Pod, template corev1.PodTemplateSpec) (bool, string) {
+	if pod.Status.Phase != corev1.PodRunning || pod.ObjectMeta.DeletionTimestamp != nil {
+		return false, ""
+	}
+
+	if hash, ok := pod.Annotations[PodTemplateHashAnnotation]; ok && hash == PodTemplateHash(template) {
+		return false, ""
+	}
+
+	live := newPodDiffView(pod.ObjectMeta, pod.Spec)
+	desired := newPodDiffView(template.ObjectMeta, template.Spec)
+
+	originalJSON, err := json.Marshal(live)
+	if err != nil {
+		logrus.Errorf("failed to marshal live pod %s for diffing: %v", pod.Name, err)
+		return false, ""
+	}
+	modifiedJSON, err := json.Marshal(desired)
+	if err != nil {
+		logrus.Errorf("failed to marshal desired pod template for diffing: %v", err)
+		return false, ""
+	}
+
+	patch, err := strategicpatch.CreateTwoWayMergePatch(originalJSON, modifiedJSON, podDiffView{})
+	if err != nil {
+		logrus.Errorf("failed to diff pod %s against its template: %v", pod.Name, err)
+		return false, ""
+	}
+
+	var patchMap map[string]interface{}
+	if err := json.Unmarshal(patch, &patchMap); err != nil {
+		logrus.Errorf("failed to parse diff for pod %s: %v", pod.Name, err)
+		return false, ""
+	}
+	if len(patchMap) == 0 {
+		return false, ""
+	}
+
+	reason := firstPatchFieldPath(patchMap, "")
+	if reason == "" {
+		reason = "pod spec changed"
+	}
+	return true, reason
+}
+
+// firstPatchFieldPath walks a decoded strategic-merge-patch and returns the
+// first concrete field path it finds, skipping the patch's own bookkeeping
+// keys ($setElementOrder, $retainKeys, ...).
+func firstPatchFieldPath(patch map[string]interface{}, prefix string) string {
+	keys := make([]string, 0, len(patch))
+	for k := range patch {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if strings.HasPrefix(key, "$") {
+			continue
+		}
+		path := key
+		if prefix != "" {
+			path = prefix + "/" + key
+		}
+
+		switch value := patch[key].(type) {
+		case map[string]interface{}:
+			if nested := firstPatchFieldPath(value, path); nested != "" {
+				return nested
+			}
+		case []interface{}:
+			for i, item := range value {
+				if nestedMap, ok := item.(map[string]interface{}); ok {
+					if nested := firstPatchFieldPath(nestedMap, fmt.Sprintf("%s/%d", path, i)); nested != "" {
+						return nested
 					}
+					continue
 				}
-
-				// now we consider them equal
-				delete(cmap, container1.Name)
-			} else {
-				// container name do not match
-				return true
+				return fmt.Sprintf("%s/%d", path, i)
 			}
+			return path
+		default:
+			return path
 		}
-		if len(cmap) != 0 {
-			// one or more containers do not match
-			return true
-		}
 	}
-	return false
+	return ""
+}
+
+// PodTemplateHash returns a stable, short hash of template's normalized
+// spec, labels, and annotations. The controller stamps this onto created
+// pods under PodTemplateHashAnnotation so PodNeedsReplacement can
+// short-circuit the diff once hashes match.
+func PodTemplateHash(template corev1.PodTemplateSpec) string {
+	view := newPodDiffView(template.ObjectMeta, template.Spec)
+
+	data, err := json.Marshal(view)
+	if err != nil {
+		logrus.Errorf("failed to marshal pod template for hashing: %v", err)
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:10]
+}
+
+// StampPodTemplateHash sets PodTemplateHashAnnotation on pod to
+// PodTemplateHash(template). The controller must call this on every pod it
+// builds from a RayCluster's head/worker template before creating it, so
+// that a later PodNeedsReplacement call can short-circuit the diff once the
+// live pod's hash matches the current template.
+func StampPodTemplateHash(pod *corev1.Pod, template corev1.PodTemplateSpec) {
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[PodTemplateHashAnnotation] = PodTemplateHash(template)
 }
 
 // CompareJsonStruct This is a way to better compare if two objects are the same when they are json/yaml structs. reflect.DeepEqual will fail in some cases.
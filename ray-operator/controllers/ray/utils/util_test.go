@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func basePodAndTemplate() (corev1.Pod, corev1.PodTemplateSpec) {
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"app": "ray"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "ray", Image: "ray:2.0"}},
+		},
+	}
+
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ray-head-abc",
+			// Labels the controller stamps on every live pod, never present
+			// on the raw template.
+			Labels: map[string]string{
+				"app":               "ray",
+				RayClusterLabelKey:  "my-cluster",
+				RayNodeTypeLabelKey: "head",
+			},
+		},
+		Spec: *template.Spec.DeepCopy(),
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+	return pod, template
+}
+
+func TestPodNeedsReplacementIgnoresControllerManagedLabels(t *testing.T) {
+	pod, template := basePodAndTemplate()
+
+	needsReplacement, reason := PodNeedsReplacement(pod, template)
+	if needsReplacement {
+		t.Errorf("PodNeedsReplacement() = true (reason %q), want false: controller-managed labels must not trigger a diff", reason)
+	}
+}
+
+func TestPodNeedsReplacementIgnoresDefaultTolerations(t *testing.T) {
+	pod, template := basePodAndTemplate()
+
+	// A cluster operator running with a non-default
+	// --default-not-ready-toleration-seconds/--default-unreachable-toleration-seconds
+	// still counts as admission-injected: the match must not depend on the
+	// stock 300s value.
+	seconds := int64(45)
+	pod.Spec.Tolerations = []corev1.Toleration{
+		{Key: "node.kubernetes.io/not-ready", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute, TolerationSeconds: &seconds},
+		{Key: "node.kubernetes.io/unreachable", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute, TolerationSeconds: &seconds},
+	}
+
+	needsReplacement, reason := PodNeedsReplacement(pod, template)
+	if needsReplacement {
+		t.Errorf("PodNeedsReplacement() = true (reason %q), want false: default admission tolerations must not trigger a diff regardless of the configured TolerationSeconds", reason)
+	}
+}
+
+func TestPodNeedsReplacementDetectsUserTolerateForeverRemoved(t *testing.T) {
+	pod, template := basePodAndTemplate()
+
+	// A nil TolerationSeconds (tolerate indefinitely) for these taint keys is
+	// a deliberate user choice, not an admission default, and must still be
+	// diffed if the template no longer declares it.
+	pod.Spec.Tolerations = []corev1.Toleration{
+		{Key: "node.kubernetes.io/not-ready", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+	}
+
+	needsReplacement, _ := PodNeedsReplacement(pod, template)
+	if !needsReplacement {
+		t.Error("PodNeedsReplacement() = false, want true: a user-authored tolerate-forever toleration was dropped from the template")
+	}
+}
+
+func TestPodNeedsReplacementDetectsImageChange(t *testing.T) {
+	pod, template := basePodAndTemplate()
+	template.Spec.Containers[0].Image = "ray:2.1"
+
+	needsReplacement, reason := PodNeedsReplacement(pod, template)
+	if !needsReplacement {
+		t.Fatal("PodNeedsReplacement() = false, want true for an image change")
+	}
+	if reason == "" {
+		t.Error("PodNeedsReplacement() reason is empty, want a field path")
+	}
+}
+
+func TestPodNeedsReplacementDetectsUserTolerationChange(t *testing.T) {
+	pod, template := basePodAndTemplate()
+	pod.Spec.Tolerations = []corev1.Toleration{
+		{Key: "custom", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	}
+
+	needsReplacement, _ := PodNeedsReplacement(pod, template)
+	if !needsReplacement {
+		t.Error("PodNeedsReplacement() = false, want true: user-specified toleration removed from template")
+	}
+}
+
+func TestPodNeedsReplacementSkipsNonRunningPods(t *testing.T) {
+	pod, template := basePodAndTemplate()
+	pod.Status.Phase = corev1.PodPending
+	template.Spec.Containers[0].Image = "ray:2.1"
+
+	needsReplacement, _ := PodNeedsReplacement(pod, template)
+	if needsReplacement {
+		t.Error("PodNeedsReplacement() = true, want false for a pod that isn't Running yet")
+	}
+}
+
+func TestPodNeedsReplacementShortCircuitsOnMatchingHash(t *testing.T) {
+	pod, template := basePodAndTemplate()
+	StampPodTemplateHash(&pod, template)
+	// Drift the live pod's spec without touching the template. A real diff
+	// would flag this, but the matching hash annotation must short-circuit
+	// before the diff ever runs.
+	pod.Spec.Containers[0].Image = "ray:stale-but-hash-says-current"
+
+	needsReplacement, _ := PodNeedsReplacement(pod, template)
+	if needsReplacement {
+		t.Error("PodNeedsReplacement() = true, want false: matching hash annotation should short-circuit the diff")
+	}
+}
+
+func TestPodTemplateHashStableAndSensitive(t *testing.T) {
+	_, template := basePodAndTemplate()
+	other := *template.DeepCopy()
+	other.Spec.Containers[0].Image = "ray:2.1"
+
+	if PodTemplateHash(template) != PodTemplateHash(template) {
+		t.Error("PodTemplateHash() is not deterministic for the same template")
+	}
+	if PodTemplateHash(template) == PodTemplateHash(other) {
+		t.Error("PodTemplateHash() did not change when the template's image changed")
+	}
+}
+
+func TestStampPodTemplateHash(t *testing.T) {
+	pod, template := basePodAndTemplate()
+	pod.Annotations = nil
+
+	StampPodTemplateHash(&pod, template)
+
+	if pod.Annotations[PodTemplateHashAnnotation] != PodTemplateHash(template) {
+		t.Error("StampPodTemplateHash() did not set the expected hash annotation")
+	}
+}